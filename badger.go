@@ -0,0 +1,484 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+	"github.com/dgraph-io/badger/v4/pb"
+)
+
+// BadgerStore is an embedded, on-disk Store backed by BadgerDB. It needs no
+// server, so it's useful for tests and small deployments where running a
+// Redis or Postgres instance isn't worth it.
+type BadgerStore struct {
+	db     *badger.DB
+	meta   BadgerMeta
+	seq    *badger.Sequence
+	gcCtx  context.Context
+	cancel context.CancelFunc
+	ticker *time.Ticker
+}
+
+type BadgerMeta struct {
+	Path       string `json:"path" yaml:"path"`
+	GCInterval int64  `json:"gcInterval" yaml:"gcInterval"`
+}
+
+// NewBadgerStore returns a Store backed by the Badger database at
+// meta.Path, sharing an already-open database from the registry when one
+// exists for the same path.
+func NewBadgerStore(meta BadgerMeta) (Store, error) {
+	key := badgerRegistryKey(meta)
+	return acquireStore(key, func() (Store, error) {
+		return newBadgerStore(meta)
+	})
+}
+
+func badgerRegistryKey(meta BadgerMeta) string {
+	return fmt.Sprintf("badger://%s", meta.Path)
+}
+
+func newBadgerStore(meta BadgerMeta) (*BadgerStore, error) {
+	opts := badger.DefaultOptions(meta.Path)
+	opts.Logger = nil
+
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open badger store: %w", err)
+	}
+
+	seq, err := db.GetSequence([]byte("generator/id"), 100)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create id sequence: %w", err)
+	}
+
+	gcInterval := meta.GCInterval
+	if gcInterval <= 0 {
+		gcInterval = 300
+	}
+	meta.GCInterval = gcInterval
+
+	gcCtx, cancel := context.WithCancel(context.Background())
+	store := &BadgerStore{
+		db:     db,
+		meta:   meta,
+		seq:    seq,
+		gcCtx:  gcCtx,
+		cancel: cancel,
+		ticker: time.NewTicker(time.Duration(gcInterval) * time.Second),
+	}
+
+	go store.valueLogGCRoutine()
+	return store, nil
+}
+
+// valueLogGCRoutine periodically reclaims space in Badger's value log.
+// Unlike Postgres/Redis, Badger expires TTL'd entries natively, so this
+// routine only exists to keep the on-disk log compact.
+func (s *BadgerStore) valueLogGCRoutine() {
+	log.Printf("Starting Badger value log GC routine, interval: %d seconds", s.meta.GCInterval)
+
+	for {
+		select {
+		case <-s.ticker.C:
+			if err := s.RunValueLogGC(); err != nil && !errors.Is(err, badger.ErrNoRewrite) {
+				log.Printf("Error during Badger value log GC: %v", err)
+			}
+		case <-s.gcCtx.Done():
+			log.Println("Stopping Badger value log GC routine")
+			s.ticker.Stop()
+			return
+		}
+	}
+}
+
+// RunValueLogGC reclaims space from Badger's value log, rewriting it if
+// doing so would free up at least half of it.
+func (s *BadgerStore) RunValueLogGC() error {
+	return s.db.RunValueLogGC(0.5)
+}
+
+func (s *BadgerStore) Get(ctx context.Context, key string) ([]byte, *TsMeta, error) {
+	var value []byte
+	var expiresAt uint64
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			return err
+		}
+		expiresAt = item.ExpiresAt()
+		return item.Value(func(val []byte) error {
+			value = append([]byte{}, val...)
+			return nil
+		})
+	})
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil, nil, ErrKeyNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get record: %w", err)
+	}
+
+	meta := &TsMeta{CreatedAt: time.Now().Format(time.RFC3339)}
+	if expiresAt > 0 {
+		meta.ExpiresAt = time.Unix(int64(expiresAt), 0).Format(time.RFC3339)
+	}
+
+	return value, meta, nil
+}
+
+// Set creates key if it does not already exist, returning ErrKeyAlreadyExists
+// otherwise - callers wanting an overwrite should use Update instead. This
+// matches PgStore.Set so the Store interface has one create-only semantic
+// across providers.
+func (s *BadgerStore) Set(ctx context.Context, key string, value []byte) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		if _, err := txn.Get([]byte(key)); err == nil {
+			return ErrKeyAlreadyExists
+		} else if !errors.Is(err, badger.ErrKeyNotFound) {
+			return err
+		}
+		return txn.SetEntry(badger.NewEntry([]byte(key), value))
+	})
+}
+
+func (s *BadgerStore) SetWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		if _, err := txn.Get([]byte(key)); err == nil {
+			return ErrKeyAlreadyExists
+		} else if !errors.Is(err, badger.ErrKeyNotFound) {
+			return err
+		}
+		return txn.SetEntry(badger.NewEntry([]byte(key), value).WithTTL(duration))
+	})
+}
+
+func (s *BadgerStore) Update(ctx context.Context, key string, value []byte) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		if _, err := txn.Get([]byte(key)); err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return ErrKeyNotFound
+			}
+			return err
+		}
+		return txn.SetEntry(badger.NewEntry([]byte(key), value))
+	})
+}
+
+func (s *BadgerStore) UpdateWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		if _, err := txn.Get([]byte(key)); err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return ErrKeyNotFound
+			}
+			return err
+		}
+		return txn.SetEntry(badger.NewEntry([]byte(key), value).WithTTL(duration))
+	})
+}
+
+func (s *BadgerStore) Delete(ctx context.Context, key string) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(key))
+	})
+}
+
+func (s *BadgerStore) GetNextID(ctx context.Context) (int64, error) {
+	id, err := s.seq.Next()
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate ID: %w", err)
+	}
+	return int64(id), nil
+}
+
+func (s *BadgerStore) MGet(ctx context.Context, keys []string) ([]KeyValuePair, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	pairs := make([]KeyValuePair, 0, len(keys))
+	err := s.db.View(func(txn *badger.Txn) error {
+		for _, key := range keys {
+			item, err := txn.Get([]byte(key))
+			if err != nil {
+				if errors.Is(err, badger.ErrKeyNotFound) {
+					continue
+				}
+				return err
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			pairs = append(pairs, KeyValuePair{Key: []byte(key), Value: value})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mget records: %w", err)
+	}
+
+	return pairs, nil
+}
+
+func (s *BadgerStore) MSet(ctx context.Context, pairs []KeyValuePair) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	return s.db.Update(func(txn *badger.Txn) error {
+		for _, pair := range pairs {
+			if err := txn.SetEntry(badger.NewEntry(pair.Key, pair.Value)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Scan walks keys sharing prefix in Badger's native sorted order, starting
+// strictly after cursor, mirroring the keyset pagination PgStore.Scan uses.
+func (s *BadgerStore) Scan(ctx context.Context, prefix string, cursor string, count int) ([]KeyValuePair, string, error) {
+	pairs := make([]KeyValuePair, 0, count)
+	next := ""
+	prefixBytes := []byte(prefix)
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefixBytes
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		seek := prefixBytes
+		if cursor != "" {
+			seek = append([]byte(cursor), 0)
+		}
+
+		for it.Seek(seek); it.ValidForPrefix(prefixBytes) && len(pairs) < count; it.Next() {
+			item := it.Item()
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			pairs = append(pairs, KeyValuePair{Key: append([]byte{}, item.Key()...), Value: value})
+		}
+
+		if len(pairs) > 0 && it.ValidForPrefix(prefixBytes) {
+			next = string(pairs[len(pairs)-1].Key)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scan records: %w", err)
+	}
+
+	return pairs, next, nil
+}
+
+// CompareAndSwap atomically replaces value with new only if it currently
+// equals old. Badger's transaction conflict detection makes this safe
+// without an explicit watch/retry loop: a concurrent writer touching key
+// aborts one of the two transactions.
+func (s *BadgerStore) CompareAndSwap(ctx context.Context, key string, old, new []byte) (bool, error) {
+	swapped := false
+
+	err := s.db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		current, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(current, old) {
+			return nil
+		}
+
+		if err := txn.SetEntry(badger.NewEntry([]byte(key), new)); err != nil {
+			return err
+		}
+		swapped = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to compare-and-swap key: %w", err)
+	}
+
+	return swapped, nil
+}
+
+// Txn runs fn inside a native Badger transaction: readOnly uses db.View for
+// a consistent read snapshot, otherwise db.Update gives fn a read-write
+// transaction that commits atomically when fn returns nil.
+func (s *BadgerStore) Txn(ctx context.Context, fn func(Tx) error, readOnly bool) error {
+	run := s.db.Update
+	if readOnly {
+		run = s.db.View
+	}
+
+	return run(func(txn *badger.Txn) error {
+		return fn(&badgerTx{txn: txn})
+	})
+}
+
+// badgerTx implements Tx against an in-flight *badger.Txn.
+type badgerTx struct {
+	txn *badger.Txn
+}
+
+func (t *badgerTx) Get(ctx context.Context, key string) ([]byte, *TsMeta, error) {
+	item, err := t.txn.Get([]byte(key))
+	if err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil, nil, ErrKeyNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get record: %w", err)
+	}
+
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	meta := &TsMeta{CreatedAt: time.Now().Format(time.RFC3339)}
+	if expiresAt := item.ExpiresAt(); expiresAt > 0 {
+		meta.ExpiresAt = time.Unix(int64(expiresAt), 0).Format(time.RFC3339)
+	}
+
+	return value, meta, nil
+}
+
+func (t *badgerTx) Set(ctx context.Context, key string, value []byte) error {
+	if _, err := t.txn.Get([]byte(key)); err == nil {
+		return ErrKeyAlreadyExists
+	} else if !errors.Is(err, badger.ErrKeyNotFound) {
+		return err
+	}
+	return t.txn.SetEntry(badger.NewEntry([]byte(key), value))
+}
+
+func (t *badgerTx) SetWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	if _, err := t.txn.Get([]byte(key)); err == nil {
+		return ErrKeyAlreadyExists
+	} else if !errors.Is(err, badger.ErrKeyNotFound) {
+		return err
+	}
+	return t.txn.SetEntry(badger.NewEntry([]byte(key), value).WithTTL(duration))
+}
+
+func (t *badgerTx) Update(ctx context.Context, key string, value []byte) error {
+	if _, err := t.txn.Get([]byte(key)); err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return ErrKeyNotFound
+		}
+		return err
+	}
+	return t.txn.SetEntry(badger.NewEntry([]byte(key), value))
+}
+
+func (t *badgerTx) UpdateWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	if _, err := t.txn.Get([]byte(key)); err != nil {
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return ErrKeyNotFound
+		}
+		return err
+	}
+	return t.txn.SetEntry(badger.NewEntry([]byte(key), value).WithTTL(duration))
+}
+
+func (t *badgerTx) Delete(ctx context.Context, key string) error {
+	return t.txn.Delete([]byte(key))
+}
+
+func (t *badgerTx) Scan(ctx context.Context, prefix string, cursor string, count int) ([]KeyValuePair, string, error) {
+	pairs := make([]KeyValuePair, 0, count)
+	prefixBytes := []byte(prefix)
+
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefixBytes
+	it := t.txn.NewIterator(opts)
+	defer it.Close()
+
+	seek := prefixBytes
+	if cursor != "" {
+		seek = append([]byte(cursor), 0)
+	}
+
+	for it.Seek(seek); it.ValidForPrefix(prefixBytes) && len(pairs) < count; it.Next() {
+		item := it.Item()
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return nil, "", err
+		}
+		pairs = append(pairs, KeyValuePair{Key: append([]byte{}, item.Key()...), Value: value})
+	}
+
+	next := ""
+	if len(pairs) > 0 && it.ValidForPrefix(prefixBytes) {
+		next = string(pairs[len(pairs)-1].Key)
+	}
+
+	return pairs, next, nil
+}
+
+// Subscribe streams changes to keys under keyPattern, treated as a literal
+// prefix since Badger's native Subscribe only matches prefixes rather than
+// full globs. A tombstone (empty value) is reported as Delete; everything
+// else as Set. The returned channel closes when ctx is canceled.
+func (s *BadgerStore) Subscribe(ctx context.Context, keyPattern string) (<-chan KeyEvent, error) {
+	events := make(chan KeyEvent)
+	subCtx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		defer close(events)
+		defer cancel()
+
+		err := s.db.Subscribe(subCtx, func(kv *badger.KVList) error {
+			for _, item := range kv.Kv {
+				op := KeyEventSet
+				if len(item.Value) == 0 {
+					op = KeyEventDelete
+				}
+
+				event := KeyEvent{Op: op, Key: string(item.Key), Value: item.Value, At: time.Now()}
+				select {
+				case events <- event:
+				case <-subCtx.Done():
+					return subCtx.Err()
+				}
+			}
+			return nil
+		}, []pb.Match{{Prefix: []byte(keyPattern)}})
+
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("badger subscribe error: %v", err)
+		}
+	}()
+
+	return events, nil
+}
+
+func (s *BadgerStore) CloseConn() error {
+	s.cancel()
+	s.ticker.Stop()
+
+	if err := s.seq.Release(); err != nil {
+		return fmt.Errorf("failed to release id sequence: %w", err)
+	}
+
+	return s.db.Close()
+}
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func newTestBadgerStore(t *testing.T) *BadgerStore {
+	t.Helper()
+
+	s, err := newBadgerStore(BadgerMeta{Path: t.TempDir()})
+	if err != nil {
+		t.Fatalf("newBadgerStore: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := s.CloseConn(); err != nil {
+			t.Errorf("CloseConn: %v", err)
+		}
+	})
+	return s
+}
+
+func TestBadgerStoreSetRejectsExistingKey(t *testing.T) {
+	ctx := context.Background()
+	s := newTestBadgerStore(t)
+
+	if err := s.Set(ctx, "k", []byte("v1")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	err := s.Set(ctx, "k", []byte("v2"))
+	if !errors.Is(err, ErrKeyAlreadyExists) {
+		t.Fatalf("Set on existing key: got %v, want ErrKeyAlreadyExists", err)
+	}
+
+	value, _, err := s.Get(ctx, "k")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(value) != "v1" {
+		t.Fatalf("Get after rejected Set: got %q, want %q", value, "v1")
+	}
+}
+
+func TestBadgerStoreCompareAndSwap(t *testing.T) {
+	ctx := context.Background()
+	s := newTestBadgerStore(t)
+
+	if err := s.Set(ctx, "k", []byte("v1")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	swapped, err := s.CompareAndSwap(ctx, "k", []byte("wrong"), []byte("v2"))
+	if err != nil {
+		t.Fatalf("CompareAndSwap: %v", err)
+	}
+	if swapped {
+		t.Fatal("CompareAndSwap swapped on a mismatched old value")
+	}
+
+	swapped, err = s.CompareAndSwap(ctx, "k", []byte("v1"), []byte("v2"))
+	if err != nil {
+		t.Fatalf("CompareAndSwap: %v", err)
+	}
+	if !swapped {
+		t.Fatal("CompareAndSwap did not swap on a matching old value")
+	}
+
+	value, _, err := s.Get(ctx, "k")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(value) != "v2" {
+		t.Fatalf("Get after CompareAndSwap: got %q, want %q", value, "v2")
+	}
+}
+
+func TestBadgerStoreTxn(t *testing.T) {
+	ctx := context.Background()
+	s := newTestBadgerStore(t)
+
+	err := s.Txn(ctx, func(tx Tx) error {
+		return tx.Set(ctx, "k", []byte("v1"))
+	}, false)
+	if err != nil {
+		t.Fatalf("Txn: %v", err)
+	}
+
+	value, _, err := s.Get(ctx, "k")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(value) != "v1" {
+		t.Fatalf("Get after Txn: got %q, want %q", value, "v1")
+	}
+}
+
+// TestBadgerStoreScanZeroCount guards against a regression where Scan
+// panicked computing nextCursor when count == 0 but matching keys existed:
+// the loop body never ran, yet the iterator was still valid for the prefix.
+func TestBadgerStoreScanZeroCount(t *testing.T) {
+	ctx := context.Background()
+	s := newTestBadgerStore(t)
+
+	if err := s.Set(ctx, "prefix/a", []byte("v")); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	pairs, next, err := s.Scan(ctx, "prefix/", "", 0)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(pairs) != 0 {
+		t.Fatalf("Scan with count=0: got %d pairs, want 0", len(pairs))
+	}
+	if next != "" {
+		t.Fatalf("Scan with count=0: got nextCursor %q, want empty", next)
+	}
+}
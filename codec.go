@@ -0,0 +1,103 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec marshals and unmarshals values at the store boundary, so callers
+// stop having to encode/decode []byte themselves around every Get/Set.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec encodes values with encoding/json.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// GobCodec encodes values with encoding/gob.
+type GobCodec struct{}
+
+func (GobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to gob-encode value: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte, v any) error {
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(v); err != nil {
+		return fmt.Errorf("failed to gob-decode value: %w", err)
+	}
+	return nil
+}
+
+// ProtoCodec encodes values that implement proto.Message using protobuf
+// wire format.
+type ProtoCodec struct{}
+
+func (ProtoCodec) Marshal(v any) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("proto codec: %T does not implement proto.Message", v)
+	}
+	return proto.Marshal(msg)
+}
+
+func (ProtoCodec) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("proto codec: %T does not implement proto.Message", v)
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+// Typed wraps a Store with a Codec so callers can Get/Set values of type T
+// directly instead of marshaling []byte at every call site.
+type Typed[T any] struct {
+	Store
+	Codec Codec
+}
+
+func (t Typed[T]) GetT(ctx context.Context, key string) (T, *TsMeta, error) {
+	var zero T
+
+	raw, meta, err := t.Store.Get(ctx, key)
+	if err != nil {
+		return zero, nil, err
+	}
+
+	var v T
+	if err := t.Codec.Unmarshal(raw, &v); err != nil {
+		return zero, nil, fmt.Errorf("failed to decode value for key %s: %w", key, err)
+	}
+
+	return v, meta, nil
+}
+
+func (t Typed[T]) SetT(ctx context.Context, key string, v T, ttl time.Duration) error {
+	raw, err := t.Codec.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode value for key %s: %w", key, err)
+	}
+
+	if ttl > 0 {
+		return t.Store.SetWithTTL(ctx, key, raw, ttl)
+	}
+	return t.Store.Set(ctx, key, raw)
+}
@@ -0,0 +1,259 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	opDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "store_op_duration_seconds",
+		Help: "Duration of Store operations in seconds.",
+	}, []string{"provider", "op"})
+
+	opTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "store_op_total",
+		Help: "Total number of Store operations.",
+	}, []string{"provider", "op", "result"})
+
+	ttlExpiredTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "store_ttl_expired_total",
+		Help: "Number of TTL-expired records removed in the most recent cleanup batch.",
+	}, []string{"provider"})
+
+	ttlCleanupBatchSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "store_ttl_cleanup_batch_size",
+		Help: "Size of each TTL cleanup batch.",
+	}, []string{"provider"})
+
+	metricsOnce sync.Once
+)
+
+// registerMetrics registers the package's Prometheus collectors exactly
+// once, even if Observed is called multiple times against different
+// registries; later calls are no-ops.
+func registerMetrics(reg prometheus.Registerer) {
+	metricsOnce.Do(func() {
+		reg.MustRegister(opDuration, opTotal, ttlExpiredTotal, ttlCleanupBatchSize)
+	})
+}
+
+// ObserveOptions configures the Observed decorator.
+type ObserveOptions struct {
+	// Provider labels every metric/log/span, e.g. "redis", "pgsql", "badger".
+	Provider string
+	// LogFormat is a mod_log_config-style template. Supported directives:
+	// %{op}o, %{key}o, %{err}o, and %D (duration). Defaults to
+	// "%{op}o %{key}o %D %{err}o".
+	LogFormat string
+	// Registerer receives the package's Prometheus collectors. Defaults to
+	// prometheus.DefaultRegisterer.
+	Registerer prometheus.Registerer
+	// TracerName names the OpenTelemetry tracer used for spans. Defaults
+	// to "store".
+	TracerName string
+}
+
+var logDirective = regexp.MustCompile(`%\{(\w+)\}o|%D`)
+
+func formatLogLine(format string, fields map[string]string) string {
+	return logDirective.ReplaceAllStringFunc(format, func(match string) string {
+		if match == "%D" {
+			return fields["duration"]
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(match, "%{"), "}o")
+		return fields[name]
+	})
+}
+
+// Observed wraps store so every method call emits a structured access log,
+// Prometheus histogram/counter, and an OpenTelemetry span.
+func Observed(store Store, opts ObserveOptions) Store {
+	if opts.LogFormat == "" {
+		opts.LogFormat = "%{op}o %{key}o %D %{err}o"
+	}
+	if opts.TracerName == "" {
+		opts.TracerName = "store"
+	}
+
+	reg := opts.Registerer
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	registerMetrics(reg)
+
+	return &observedStore{
+		Store:  store,
+		opts:   opts,
+		tracer: otel.Tracer(opts.TracerName),
+	}
+}
+
+type observedStore struct {
+	Store
+	opts   ObserveOptions
+	tracer trace.Tracer
+}
+
+// instrument runs fn, recording a log line, metrics, and a tracing span
+// for the op/key pair. fn receives the span-bound context so calls it makes
+// downstream (e.g. further Store methods) nest under the same trace.
+func (o *observedStore) instrument(ctx context.Context, op, key string, fn func(ctx context.Context) error) error {
+	start := time.Now()
+
+	ctx, span := o.tracer.Start(ctx, "store."+op, trace.WithAttributes(
+		attribute.String("db.system", o.opts.Provider),
+		attribute.String("db.operation", op),
+		attribute.String("db.statement", key),
+	))
+	defer span.End()
+
+	err := fn(ctx)
+	duration := time.Since(start)
+
+	result := "ok"
+	errStr := ""
+	if err != nil {
+		result = "error"
+		errStr = err.Error()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	opDuration.WithLabelValues(o.opts.Provider, op).Observe(duration.Seconds())
+	opTotal.WithLabelValues(o.opts.Provider, op, result).Inc()
+
+	log.Print(formatLogLine(o.opts.LogFormat, map[string]string{
+		"op":       op,
+		"key":      key,
+		"duration": duration.String(),
+		"err":      errStr,
+	}))
+
+	return err
+}
+
+func (o *observedStore) Get(ctx context.Context, key string) ([]byte, *TsMeta, error) {
+	var value []byte
+	var meta *TsMeta
+	err := o.instrument(ctx, "get", key, func(ctx context.Context) error {
+		var err error
+		value, meta, err = o.Store.Get(ctx, key)
+		return err
+	})
+	return value, meta, err
+}
+
+func (o *observedStore) Set(ctx context.Context, key string, value []byte) error {
+	return o.instrument(ctx, "set", key, func(ctx context.Context) error {
+		return o.Store.Set(ctx, key, value)
+	})
+}
+
+func (o *observedStore) SetWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	return o.instrument(ctx, "set_with_ttl", key, func(ctx context.Context) error {
+		return o.Store.SetWithTTL(ctx, key, value, duration)
+	})
+}
+
+func (o *observedStore) Update(ctx context.Context, key string, value []byte) error {
+	return o.instrument(ctx, "update", key, func(ctx context.Context) error {
+		return o.Store.Update(ctx, key, value)
+	})
+}
+
+func (o *observedStore) UpdateWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	return o.instrument(ctx, "update_with_ttl", key, func(ctx context.Context) error {
+		return o.Store.UpdateWithTTL(ctx, key, value, duration)
+	})
+}
+
+func (o *observedStore) Delete(ctx context.Context, key string) error {
+	return o.instrument(ctx, "delete", key, func(ctx context.Context) error {
+		return o.Store.Delete(ctx, key)
+	})
+}
+
+func (o *observedStore) GetNextID(ctx context.Context) (int64, error) {
+	var id int64
+	err := o.instrument(ctx, "get_next_id", "generator/id", func(ctx context.Context) error {
+		var err error
+		id, err = o.Store.GetNextID(ctx)
+		return err
+	})
+	return id, err
+}
+
+func (o *observedStore) MGet(ctx context.Context, keys []string) ([]KeyValuePair, error) {
+	var pairs []KeyValuePair
+	err := o.instrument(ctx, "mget", fmt.Sprintf("%d keys", len(keys)), func(ctx context.Context) error {
+		var err error
+		pairs, err = o.Store.MGet(ctx, keys)
+		return err
+	})
+	return pairs, err
+}
+
+func (o *observedStore) MSet(ctx context.Context, pairs []KeyValuePair) error {
+	return o.instrument(ctx, "mset", fmt.Sprintf("%d pairs", len(pairs)), func(ctx context.Context) error {
+		return o.Store.MSet(ctx, pairs)
+	})
+}
+
+func (o *observedStore) Scan(ctx context.Context, prefix string, cursor string, count int) ([]KeyValuePair, string, error) {
+	var pairs []KeyValuePair
+	var next string
+	err := o.instrument(ctx, "scan", prefix, func(ctx context.Context) error {
+		var err error
+		pairs, next, err = o.Store.Scan(ctx, prefix, cursor, count)
+		return err
+	})
+	return pairs, next, err
+}
+
+func (o *observedStore) CompareAndSwap(ctx context.Context, key string, old, new []byte) (bool, error) {
+	var swapped bool
+	err := o.instrument(ctx, "compare_and_swap", key, func(ctx context.Context) error {
+		var err error
+		swapped, err = o.Store.CompareAndSwap(ctx, key, old, new)
+		return err
+	})
+	return swapped, err
+}
+
+func (o *observedStore) Txn(ctx context.Context, fn func(Tx) error, readOnly bool) error {
+	op := "txn"
+	if readOnly {
+		op = "txn_read_only"
+	}
+	return o.instrument(ctx, op, "", func(ctx context.Context) error {
+		return o.Store.Txn(ctx, fn, readOnly)
+	})
+}
+
+func (o *observedStore) Subscribe(ctx context.Context, keyPattern string) (<-chan KeyEvent, error) {
+	var events <-chan KeyEvent
+	err := o.instrument(ctx, "subscribe", keyPattern, func(ctx context.Context) error {
+		var err error
+		events, err = o.Store.Subscribe(ctx, keyPattern)
+		return err
+	})
+	return events, err
+}
+
+func (o *observedStore) CloseConn() error {
+	return o.instrument(context.Background(), "close_conn", "", func(ctx context.Context) error {
+		return o.Store.CloseConn()
+	})
+}
@@ -2,13 +2,22 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/lib/pq"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PgStore struct {
@@ -17,11 +26,12 @@ type PgStore struct {
 	pgCtx  context.Context
 	cancel context.CancelFunc
 	ticker *time.Ticker
+	idSeq  string
 }
 
 type Record struct {
 	Key        string    `gorm:"primaryKey;index:idx_key"`
-	Value      string    `gorm:"not null"`
+	Value      []byte    `gorm:"not null;type:bytea"`
 	IsTTLBased bool      `gorm:"column:is_ttl_based;index:idx_is_ttl_based;default:false;not null"`
 	ExpiresAt  time.Time `gorm:"column:expires_at;index:idx_expires_at"`
 	Timestamp  time.Time `gorm:"column:ts;index:idx_ts"`
@@ -39,7 +49,81 @@ type PgMeta struct {
 	CronInterval int64  `json:"cronInterval" yaml:"cronInterval"`
 }
 
+// NewPostgresStore returns a Store backed by the given Postgres connection,
+// sharing an already-open pool from the registry when one exists for the
+// same user/password/host/port/database/table/sslmode.
 func NewPostgresStore(meta PgMeta) (Store, error) {
+	key := pgRegistryKey(meta)
+	return acquireStore(key, func() (Store, error) {
+		return newPostgresStore(meta)
+	})
+}
+
+// newPostgresStoreFromURI parses dsn as a postgres:// connection string, e.g.
+// "postgres://user:pw@host:port/db?sslmode=disable&tz=UTC&cron=30s&table=kv".
+func newPostgresStoreFromURI(dsn string) (Store, error) {
+	meta, err := parsePostgresURI(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return NewPostgresStore(meta)
+}
+
+func parsePostgresURI(dsn string) (PgMeta, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return PgMeta{}, fmt.Errorf("invalid postgres URI: %w", err)
+	}
+
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		host, port = u.Host, "5432"
+	}
+
+	meta := PgMeta{
+		Host:         host,
+		Port:         port,
+		DatabaseName: strings.TrimPrefix(u.Path, "/"),
+		SslMode:      "disable",
+		CronInterval: 30,
+	}
+
+	if u.User != nil {
+		meta.User = u.User.Username()
+		meta.Password, _ = u.User.Password()
+	}
+
+	q := u.Query()
+	if v := q.Get("sslmode"); v != "" {
+		meta.SslMode = v
+	}
+	if v := q.Get("tz"); v != "" {
+		meta.Timezone = v
+	}
+	if v := q.Get("table"); v != "" {
+		meta.TableName = v
+	}
+	if v := q.Get("cron"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return PgMeta{}, fmt.Errorf("invalid cron interval %q: %w", v, err)
+		}
+		meta.CronInterval = int64(d.Seconds())
+	}
+
+	return meta, nil
+}
+
+// pgRegistryKey folds auth and sslmode into the dedup key alongside
+// host/port/database/table so two callers pointed at the same
+// host/port/database with different credentials never share a pool -
+// whichever opens it first would otherwise silently win.
+func pgRegistryKey(meta PgMeta) string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?table=%s&sslmode=%s",
+		meta.User, meta.Password, meta.Host, meta.Port, meta.DatabaseName, meta.TableName, meta.SslMode)
+}
+
+func newPostgresStore(meta PgMeta) (*PgStore, error) {
 	baseConnectionString := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=postgres sslmode=%s",
 		meta.Host, meta.Port, meta.User, meta.Password, meta.SslMode)
 
@@ -74,10 +158,26 @@ func NewPostgresStore(meta PgMeta) (Store, error) {
 		db = db.Table(meta.TableName)
 	}
 
+	table := meta.TableName
+	if table == "" {
+		table = "records"
+	}
+	if err := migrateValueColumnToBytea(db, table); err != nil {
+		return nil, err
+	}
+
 	if err := db.AutoMigrate(&Record{}); err != nil {
 		return nil, err
 	}
 
+	idSeq := table + "_generator_id_seq"
+	if err := db.Exec(fmt.Sprintf(`CREATE SEQUENCE IF NOT EXISTS %s`, pq.QuoteIdentifier(idSeq))).Error; err != nil {
+		return nil, fmt.Errorf("failed to create id sequence: %w", err)
+	}
+	if err := seedIDSequenceFromLegacyCounter(db, table, idSeq); err != nil {
+		return nil, err
+	}
+
 	pgCtx, cancel := context.WithCancel(context.Background())
 	ticker := time.NewTicker(time.Duration(meta.CronInterval) * time.Second)
 	store := &PgStore{
@@ -86,6 +186,7 @@ func NewPostgresStore(meta PgMeta) (Store, error) {
 		pgCtx:  pgCtx,
 		cancel: cancel,
 		ticker: ticker,
+		idSeq:  idSeq,
 	}
 
 	go store.cleanupRoutine()
@@ -111,12 +212,217 @@ func (s *PgStore) cleanupRoutine() {
 
 func (s *PgStore) cleanExpiredRecords() error {
 	now := time.Now()
+
+	// Collect the expired rows before deleting them so each one can still
+	// be announced as an Expire event once it's gone.
+	var expired []Record
+	if err := s.db.Where("is_ttl_based = ? AND expires_at <= ?", true, now).Find(&expired).Error; err != nil {
+		return fmt.Errorf("failed to collect expired records: %w", err)
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
 	result := s.db.Where("is_ttl_based = ? AND expires_at <= ?", true, now).Delete(&Record{})
 
 	if result.RowsAffected > 0 {
 		log.Printf("Cleaned up %d expired records", result.RowsAffected)
 	}
 
+	ttlExpiredTotal.WithLabelValues("pgsql").Set(float64(len(expired)))
+	ttlCleanupBatchSize.WithLabelValues("pgsql").Observe(float64(len(expired)))
+
+	for _, record := range expired {
+		s.notify(s.pgCtx, KeyEventExpire, record.Key, record.Value)
+	}
+
+	return nil
+}
+
+// notify publishes a KeyEvent on the store_events channel via pg_notify so
+// that Subscribe callers, which hold a dedicated LISTEN connection, see it.
+func (s *PgStore) notify(ctx context.Context, op KeyEventOp, key string, value []byte) {
+	payload, err := json.Marshal(KeyEvent{Op: op, Key: key, Value: value, At: time.Now()})
+	if err != nil {
+		log.Printf("failed to marshal store event: %v", err)
+		return
+	}
+
+	if err := s.db.WithContext(ctx).Exec("SELECT pg_notify('store_events', ?)", string(payload)).Error; err != nil {
+		log.Printf("failed to emit store event: %v", err)
+	}
+}
+
+// Subscribe streams changes to keys matching keyPattern (a Redis PSUBSCRIBE-
+// style glob, so the same pattern yields the same subscriber set here as on
+// Redis). It opens a dedicated LISTEN connection via pq.Listener, since
+// GORM's pooled connections don't preserve LISTEN across queries. The
+// returned channel closes when ctx is canceled.
+func (s *PgStore) Subscribe(ctx context.Context, keyPattern string) (<-chan KeyEvent, error) {
+	matcher, err := globToRegexp(keyPattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key pattern %q: %w", keyPattern, err)
+	}
+
+	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		s.meta.Host, s.meta.Port, s.meta.User, s.meta.Password, s.meta.DatabaseName, s.meta.SslMode)
+
+	listener := pq.NewListener(connStr, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("store_events listener error: %v", err)
+		}
+	})
+
+	if err := listener.Listen("store_events"); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen on store_events: %w", err)
+	}
+
+	events := make(chan KeyEvent)
+	go func() {
+		defer close(events)
+		defer listener.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification, ok := <-listener.Notify:
+				if !ok {
+					return
+				}
+				if notification == nil {
+					continue
+				}
+
+				var event KeyEvent
+				if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil {
+					log.Printf("failed to unmarshal store event: %v", err)
+					continue
+				}
+
+				if !matcher.MatchString(event.Key) {
+					continue
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// globToRegexp translates a Redis glob pattern (the syntax PSUBSCRIBE uses
+// for keyspace notifications: '*' matches any run of characters, '?' any
+// single character, '[...]'/'[^...]' a character class, '\' escapes the
+// next character literally) into a regexp. Unlike path.Match, '*' has no
+// path-separator special case, so the same pattern matches the same keys
+// on Postgres as it does on Redis.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			sb.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		case '[':
+			end := i + 1
+			if end < len(pattern) && pattern[end] == '^' {
+				end++
+			}
+			if end < len(pattern) && pattern[end] == ']' {
+				end++
+			}
+			for end < len(pattern) && pattern[end] != ']' {
+				end++
+			}
+			if end >= len(pattern) {
+				sb.WriteString(regexp.QuoteMeta("["))
+				continue
+			}
+			sb.WriteString(pattern[i : end+1])
+			i = end
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
+
+// migrateValueColumnToBytea converts the legacy text value column to bytea
+// in place so gob/proto payloads round-trip instead of being corrupted by
+// a string cast. It's a no-op if the table doesn't exist yet (fresh install)
+// or the column is already bytea (already migrated).
+func migrateValueColumnToBytea(db *gorm.DB, table string) error {
+	if !db.Migrator().HasTable(table) {
+		return nil
+	}
+
+	var dataType string
+	if err := db.Raw(`SELECT data_type FROM information_schema.columns WHERE table_name = ? AND column_name = 'value'`, table).Scan(&dataType).Error; err != nil {
+		return fmt.Errorf("failed to inspect value column: %w", err)
+	}
+	if dataType == "bytea" {
+		return nil
+	}
+
+	query := fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN value TYPE bytea USING convert_to(value, 'UTF8')`, table)
+	if err := db.Exec(query).Error; err != nil {
+		return fmt.Errorf("failed to migrate value column to bytea: %w", err)
+	}
+
+	return nil
+}
+
+// seedIDSequenceFromLegacyCounter carries over the old GetNextID counter,
+// which used to live in the "generator/id" row of the value column, onto
+// idSeq so upgrading doesn't reset already-issued IDs back to 1 and hand
+// out duplicates. It's a no-op for fresh installs, which have no such row.
+func seedIDSequenceFromLegacyCounter(db *gorm.DB, table, idSeq string) error {
+	if !db.Migrator().HasTable(table) {
+		return nil
+	}
+
+	var raw []byte
+	query := fmt.Sprintf(`SELECT value FROM %s WHERE key = ?`, table)
+	err := db.Raw(query, "generator/id").Scan(&raw).Error
+	if err != nil {
+		return fmt.Errorf("failed to read legacy id counter: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+
+	last, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse legacy id counter %q: %w", raw, err)
+	}
+
+	setval := fmt.Sprintf(`SELECT setval('%s', ?)`, pq.QuoteIdentifier(idSeq))
+	if err := db.Exec(setval, last).Error; err != nil {
+		return fmt.Errorf("failed to seed id sequence from legacy counter: %w", err)
+	}
+
+	deleteQuery := fmt.Sprintf(`DELETE FROM %s WHERE key = ?`, table)
+	if err := db.Exec(deleteQuery, "generator/id").Error; err != nil {
+		return fmt.Errorf("failed to remove legacy id counter row: %w", err)
+	}
+
 	return nil
 }
 
@@ -135,27 +441,33 @@ func createDatabaseIfNotExists(db *gorm.DB, dbName string) error {
 	return nil
 }
 
-func (s *PgStore) Get(ctx context.Context, key string) (response []byte, err error) {
+func (s *PgStore) Get(ctx context.Context, key string) ([]byte, *TsMeta, error) {
 	var record Record
 	result := s.db.WithContext(ctx).Where("key = ?", key).First(&record)
 	if result.Error != nil {
-		if result.Error.Error() == "record not found" {
-			return nil, ErrKeyNotFound
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrKeyNotFound
 		}
-		return nil, fmt.Errorf("failed to get record: %w", result.Error)
+		return nil, nil, fmt.Errorf("failed to get record: %w", result.Error)
 	}
 
 	if record.IsTTLBased && record.ExpiresAt.Before(time.Now()) {
-		return nil, ErrKeyNotFound
+		return nil, nil, ErrKeyNotFound
+	}
+
+	meta := &TsMeta{CreatedAt: record.Timestamp.Format(time.RFC3339)}
+	if record.IsTTLBased {
+		meta.ExpiresAt = record.ExpiresAt.Format(time.RFC3339)
 	}
-	return []byte(record.Value), nil
+
+	return record.Value, meta, nil
 }
 
 func (s *PgStore) GetUIDFromEmail(ctx context.Context, pattern, email string) (string, error) {
 	var record Record
-	result := s.db.WithContext(ctx).Where("key LIKE ? AND value = ?", pattern, email).First(&record)
+	result := s.db.WithContext(ctx).Where("key LIKE ? AND value = ?", pattern, []byte(email)).First(&record)
 	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return "", ErrKeyNotFound
 		}
 		return "", fmt.Errorf("failed to get UID: %w", result.Error)
@@ -168,6 +480,110 @@ func (s *PgStore) GetUIDFromEmail(ctx context.Context, pattern, email string) (s
 	return "", ErrKeyNotFound
 }
 
+// tableName returns the configured table name, falling back to GORM's
+// default pluralized name for Record when the caller didn't set one.
+func (s *PgStore) tableName() string {
+	if s.meta.TableName != "" {
+		return s.meta.TableName
+	}
+	return "records"
+}
+
+// GetNextID hands out a monotonically increasing ID from a dedicated
+// Postgres sequence. The counter can't live in the value column: that
+// column is bytea (see migrateValueColumnToBytea) and Postgres has no
+// bytea<->bigint cast, so a sequence object is the only place left to
+// keep it.
+func (s *PgStore) GetNextID(ctx context.Context) (int64, error) {
+	query := fmt.Sprintf(`SELECT nextval('%s')`, pq.QuoteIdentifier(s.idSeq))
+
+	var id int64
+	if err := s.db.WithContext(ctx).Raw(query).Scan(&id).Error; err != nil {
+		return 0, fmt.Errorf("failed to generate ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (s *PgStore) MGet(ctx context.Context, keys []string) ([]KeyValuePair, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	var records []Record
+	result := s.db.WithContext(ctx).Where("key IN ?", keys).Find(&records)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to mget records: %w", result.Error)
+	}
+
+	now := time.Now()
+	pairs := make([]KeyValuePair, 0, len(records))
+	for _, record := range records {
+		if record.IsTTLBased && record.ExpiresAt.Before(now) {
+			continue
+		}
+		pairs = append(pairs, KeyValuePair{Key: []byte(record.Key), Value: record.Value})
+	}
+
+	return pairs, nil
+}
+
+func (s *PgStore) MSet(ctx context.Context, pairs []KeyValuePair) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	records := make([]Record, 0, len(pairs))
+	for _, pair := range pairs {
+		records = append(records, Record{
+			Key:       string(pair.Key),
+			Value:     pair.Value,
+			Timestamp: now,
+		})
+	}
+
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "ts"}),
+	}).Create(&records)
+	if result.Error != nil {
+		return fmt.Errorf("failed to mset records: %w", result.Error)
+	}
+
+	return nil
+}
+
+// Scan keyset-paginates over keys sharing prefix, starting strictly after
+// cursor. It returns the next cursor to pass back in, or "" when exhausted.
+func (s *PgStore) Scan(ctx context.Context, prefix string, cursor string, count int) ([]KeyValuePair, string, error) {
+	var records []Record
+	result := s.db.WithContext(ctx).
+		Where("key LIKE ? AND key > ?", prefix+"%", cursor).
+		Order("key").
+		Limit(count).
+		Find(&records)
+	if result.Error != nil {
+		return nil, "", fmt.Errorf("failed to scan records: %w", result.Error)
+	}
+
+	now := time.Now()
+	pairs := make([]KeyValuePair, 0, len(records))
+	for _, record := range records {
+		if record.IsTTLBased && record.ExpiresAt.Before(now) {
+			continue
+		}
+		pairs = append(pairs, KeyValuePair{Key: []byte(record.Key), Value: record.Value})
+	}
+
+	next := ""
+	if len(records) > 0 && len(records) == count {
+		next = records[len(records)-1].Key
+	}
+
+	return pairs, next, nil
+}
+
 func (s *PgStore) keyExists(key string) (bool, error) {
 	var count int64
 	result := s.db.Model(&Record{}).Where("key = ?", key).Count(&count)
@@ -188,7 +604,7 @@ func (s *PgStore) Set(ctx context.Context, key string, value []byte) error {
 
 	record := Record{
 		Key:        key,
-		Value:      string(value),
+		Value:      value,
 		IsTTLBased: false,
 		Timestamp:  time.Now(),
 	}
@@ -198,6 +614,7 @@ func (s *PgStore) Set(ctx context.Context, key string, value []byte) error {
 		return fmt.Errorf("failed to set record: %w", result.Error)
 	}
 
+	s.notify(ctx, KeyEventSet, key, value)
 	return nil
 }
 
@@ -213,7 +630,7 @@ func (s *PgStore) SetWithTTL(ctx context.Context, key string, value []byte, dura
 	now := time.Now()
 	record := Record{
 		Key:        key,
-		Value:      string(value),
+		Value:      value,
 		IsTTLBased: true,
 		ExpiresAt:  now.Add(duration),
 		Timestamp:  now,
@@ -224,6 +641,7 @@ func (s *PgStore) SetWithTTL(ctx context.Context, key string, value []byte, dura
 		return fmt.Errorf("failed to set record with TTL: %w", result.Error)
 	}
 
+	s.notify(ctx, KeyEventSet, key, value)
 	return nil
 }
 
@@ -231,8 +649,8 @@ func (s *PgStore) Update(ctx context.Context, key string, value []byte) error {
 	result := s.db.Model(&Record{}).
 		Where("key = ?", key).
 		Updates(map[string]interface{}{
-			"value":     string(value),
-			"timestamp": time.Now(),
+			"value": value,
+			"ts":    time.Now(),
 		})
 
 	if result.Error != nil {
@@ -243,6 +661,7 @@ func (s *PgStore) Update(ctx context.Context, key string, value []byte) error {
 		return ErrKeyNotFound
 	}
 
+	s.notify(ctx, KeyEventUpdate, key, value)
 	return nil
 }
 
@@ -251,10 +670,10 @@ func (s *PgStore) UpdateWithTTL(ctx context.Context, key string, value []byte, d
 	result := s.db.Model(&Record{}).
 		Where("key = ?", key).
 		Updates(map[string]interface{}{
-			"value":        string(value),
+			"value":        value,
 			"is_ttl_based": true,
 			"expires_at":   now.Add(duration),
-			"timestamp":    now,
+			"ts":           now,
 		})
 
 	if result.Error != nil {
@@ -265,9 +684,13 @@ func (s *PgStore) UpdateWithTTL(ctx context.Context, key string, value []byte, d
 		return ErrKeyNotFound
 	}
 
+	s.notify(ctx, KeyEventUpdate, key, value)
 	return nil
 }
 
+// Delete removes key, matching the no-op-on-missing-key contract Redis/Badger
+// Delete already have: deleting an absent key is not an error, and no
+// KeyEventDelete notification fires since nothing actually changed.
 func (s *PgStore) Delete(ctx context.Context, key string) error {
 	result := s.db.Where("key = ?", key).Delete(&Record{})
 	if result.Error != nil {
@@ -275,12 +698,162 @@ func (s *PgStore) Delete(ctx context.Context, key string) error {
 	}
 
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("key %s not found", key)
+		return nil
+	}
+
+	s.notify(ctx, KeyEventDelete, key, nil)
+	return nil
+}
+
+// CompareAndSwap atomically replaces value with new only if it currently
+// equals old, checking RowsAffected to report whether the swap happened.
+func (s *PgStore) CompareAndSwap(ctx context.Context, key string, old, new []byte) (bool, error) {
+	result := s.db.WithContext(ctx).Model(&Record{}).
+		Where("key = ? AND value = ?", key, old).
+		Updates(map[string]interface{}{
+			"value": new,
+			"ts":    time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to compare-and-swap record: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Txn runs fn inside a real *sql.Tx. readOnly begins the transaction with
+// RepeatableRead isolation so fn sees a stable snapshot across multiple
+// Get/Scan calls, safe from phantom rows left by a concurrent TTL cleaner.
+func (s *PgStore) Txn(ctx context.Context, fn func(Tx) error, readOnly bool) error {
+	opts := &sql.TxOptions{ReadOnly: readOnly}
+	if readOnly {
+		opts.Isolation = sql.LevelRepeatableRead
+	}
+
+	tx := s.db.WithContext(ctx).Begin(opts)
+	if tx.Error != nil {
+		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+
+	if err := fn(&pgTx{tx: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// pgTx implements Tx against an in-flight *gorm.DB transaction.
+type pgTx struct {
+	tx *gorm.DB
+}
+
+func (t *pgTx) Get(ctx context.Context, key string) ([]byte, *TsMeta, error) {
+	var record Record
+	result := t.tx.Where("key = ?", key).First(&record)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrKeyNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get record: %w", result.Error)
+	}
+
+	if record.IsTTLBased && record.ExpiresAt.Before(time.Now()) {
+		return nil, nil, ErrKeyNotFound
+	}
+
+	meta := &TsMeta{CreatedAt: record.Timestamp.Format(time.RFC3339)}
+	if record.IsTTLBased {
+		meta.ExpiresAt = record.ExpiresAt.Format(time.RFC3339)
+	}
+
+	return record.Value, meta, nil
+}
+
+func (t *pgTx) Set(ctx context.Context, key string, value []byte) error {
+	record := Record{Key: key, Value: value, Timestamp: time.Now()}
+	if err := t.tx.Create(&record).Error; err != nil {
+		return fmt.Errorf("failed to set record: %w", err)
+	}
+	return nil
+}
+
+func (t *pgTx) SetWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	now := time.Now()
+	record := Record{Key: key, Value: value, IsTTLBased: true, ExpiresAt: now.Add(duration), Timestamp: now}
+	if err := t.tx.Create(&record).Error; err != nil {
+		return fmt.Errorf("failed to set record with TTL: %w", err)
+	}
+	return nil
+}
+
+func (t *pgTx) Update(ctx context.Context, key string, value []byte) error {
+	result := t.tx.Model(&Record{}).Where("key = ?", key).Updates(map[string]interface{}{
+		"value": value,
+		"ts":    time.Now(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update record: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
+
+func (t *pgTx) UpdateWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	now := time.Now()
+	result := t.tx.Model(&Record{}).Where("key = ?", key).Updates(map[string]interface{}{
+		"value":        value,
+		"is_ttl_based": true,
+		"expires_at":   now.Add(duration),
+		"ts":           now,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update record with TTL: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrKeyNotFound
 	}
+	return nil
+}
 
+func (t *pgTx) Delete(ctx context.Context, key string) error {
+	result := t.tx.Where("key = ?", key).Delete(&Record{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete record: %w", result.Error)
+	}
 	return nil
 }
 
+func (t *pgTx) Scan(ctx context.Context, prefix string, cursor string, count int) ([]KeyValuePair, string, error) {
+	var records []Record
+	result := t.tx.Where("key LIKE ? AND key > ?", prefix+"%", cursor).Order("key").Limit(count).Find(&records)
+	if result.Error != nil {
+		return nil, "", fmt.Errorf("failed to scan records: %w", result.Error)
+	}
+
+	now := time.Now()
+	pairs := make([]KeyValuePair, 0, len(records))
+	for _, record := range records {
+		if record.IsTTLBased && record.ExpiresAt.Before(now) {
+			continue
+		}
+		pairs = append(pairs, KeyValuePair{Key: []byte(record.Key), Value: record.Value})
+	}
+
+	next := ""
+	if len(records) > 0 && len(records) == count {
+		next = records[len(records)-1].Key
+	}
+
+	return pairs, next, nil
+}
+
 func (s *PgStore) CloseConn() error {
 	s.cancel()
 	sqlDB, err := s.db.DB()
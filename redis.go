@@ -1,13 +1,23 @@
 package store
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	redis "github.com/redis/go-redis/v9"
 )
 
+// casMaxRetries bounds how many times CompareAndSwap retries after a
+// concurrent writer invalidates the WATCHed key.
+const casMaxRetries = 10
+
 type RedisStore struct {
 	client *redis.Client
 	meta   RedisMeta
@@ -18,13 +28,79 @@ type RedisMeta struct {
 	Port     string `json:"port" yaml:"port"`
 	Password string `json:"password" yaml:"password"`
 	DB       int    `json:"db" yaml:"db"`
+	PoolSize int    `json:"poolSize" yaml:"poolSize"`
 }
 
+// NewRedisStore returns a Store backed by the given Redis connection,
+// sharing an already-open connection from the registry when one exists
+// for the same password/host/port/db.
 func NewRedisStore(meta RedisMeta) (Store, error) {
+	key := redisRegistryKey(meta)
+	return acquireStore(key, func() (Store, error) {
+		return newRedisStore(meta)
+	})
+}
+
+// newRedisStoreFromURI parses dsn as a redis:// connection string, e.g.
+// "redis://:password@host:port/db?pool_size=10".
+func newRedisStoreFromURI(dsn string) (Store, error) {
+	meta, err := parseRedisURI(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return NewRedisStore(meta)
+}
+
+func parseRedisURI(dsn string) (RedisMeta, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return RedisMeta{}, fmt.Errorf("invalid redis URI: %w", err)
+	}
+
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		host, port = u.Host, "6379"
+	}
+
+	meta := RedisMeta{Host: host, Port: port}
+
+	if u.User != nil {
+		meta.Password, _ = u.User.Password()
+	}
+
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		n, err := strconv.Atoi(db)
+		if err != nil {
+			return RedisMeta{}, fmt.Errorf("invalid redis db %q: %w", db, err)
+		}
+		meta.DB = n
+	}
+
+	if v := u.Query().Get("pool_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return RedisMeta{}, fmt.Errorf("invalid pool_size %q: %w", v, err)
+		}
+		meta.PoolSize = n
+	}
+
+	return meta, nil
+}
+
+// redisRegistryKey folds the password into the dedup key alongside
+// host/port/db so two callers targeting the same database with different
+// credentials never share a connection - whichever opens it first would
+// otherwise silently win.
+func redisRegistryKey(meta RedisMeta) string {
+	return fmt.Sprintf("redis://%s@%s:%s/%d", meta.Password, meta.Host, meta.Port, meta.DB)
+}
+
+func newRedisStore(meta RedisMeta) (*RedisStore, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("%s:%s", meta.Host, meta.Port),
 		Password: meta.Password,
 		DB:       meta.DB,
+		PoolSize: meta.PoolSize,
 	})
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -54,7 +130,7 @@ func (r *RedisStore) Get(ctx context.Context, key string) ([]byte, *TsMeta, erro
 	val, err := r.client.Get(ctx, fullKey).Bytes()
 	if err != nil {
 		if err == redis.Nil {
-			return nil, nil, fmt.Errorf("key not found: %s", key)
+			return nil, nil, ErrKeyNotFound
 		}
 		return nil, nil, err
 	}
@@ -74,11 +150,51 @@ func (r *RedisStore) Get(ctx context.Context, key string) ([]byte, *TsMeta, erro
 	return val, meta, nil
 }
 
+// Set creates key if it does not already exist, returning ErrKeyAlreadyExists
+// otherwise - callers wanting an overwrite should use Update instead. This
+// matches PgStore.Set so the Store interface has one create-only semantic
+// across providers.
 func (r *RedisStore) Set(ctx context.Context, key string, value []byte) error {
-	return r.client.Set(ctx, key, value, 0).Err()
+	ok, err := r.client.SetNX(ctx, key, value, 0).Result()
+	if err != nil {
+		return fmt.Errorf("failed to set key: %w", err)
+	}
+	if !ok {
+		return ErrKeyAlreadyExists
+	}
+	return nil
 }
 
 func (r *RedisStore) SetWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	ok, err := r.client.SetNX(ctx, key, value, duration).Result()
+	if err != nil {
+		return fmt.Errorf("failed to set key: %w", err)
+	}
+	if !ok {
+		return ErrKeyAlreadyExists
+	}
+	return nil
+}
+
+func (r *RedisStore) Update(ctx context.Context, key string, value []byte) error {
+	exists, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check key existence: %w", err)
+	}
+	if exists == 0 {
+		return ErrKeyNotFound
+	}
+	return r.client.Set(ctx, key, value, redis.KeepTTL).Err()
+}
+
+func (r *RedisStore) UpdateWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	exists, err := r.client.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check key existence: %w", err)
+	}
+	if exists == 0 {
+		return ErrKeyNotFound
+	}
 	return r.client.Set(ctx, key, value, duration).Err()
 }
 
@@ -86,6 +202,294 @@ func (r *RedisStore) Delete(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
 
+func (r *RedisStore) MGet(ctx context.Context, keys []string) ([]KeyValuePair, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	vals, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to mget keys: %w", err)
+	}
+
+	pairs := make([]KeyValuePair, 0, len(keys))
+	for i, val := range vals {
+		if val == nil {
+			continue
+		}
+		strVal, ok := val.(string)
+		if !ok {
+			continue
+		}
+		pairs = append(pairs, KeyValuePair{Key: []byte(keys[i]), Value: []byte(strVal)})
+	}
+
+	return pairs, nil
+}
+
+func (r *RedisStore) MSet(ctx context.Context, pairs []KeyValuePair) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(pairs)*2)
+	for _, pair := range pairs {
+		args = append(args, string(pair.Key), pair.Value)
+	}
+
+	if err := r.client.MSet(ctx, args...).Err(); err != nil {
+		return fmt.Errorf("failed to mset keys: %w", err)
+	}
+
+	return nil
+}
+
+// Scan walks the keyspace using Redis's cursor-based SCAN/MATCH/COUNT
+// protocol, treating cursor as the opaque token returned by the previous
+// call ("" to start from the beginning).
+func (r *RedisStore) Scan(ctx context.Context, prefix string, cursor string, count int) ([]KeyValuePair, string, error) {
+	var redisCursor uint64
+	if cursor != "" {
+		parsed, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid cursor: %w", err)
+		}
+		redisCursor = parsed
+	}
+
+	keys, nextCursor, err := r.client.Scan(ctx, redisCursor, prefix+"*", int64(count)).Result()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scan keys: %w", err)
+	}
+
+	pairs, err := r.MGet(ctx, keys)
+	if err != nil {
+		return nil, "", err
+	}
+
+	next := ""
+	if nextCursor != 0 {
+		next = strconv.FormatUint(nextCursor, 10)
+	}
+
+	return pairs, next, nil
+}
+
+// CompareAndSwap atomically replaces value with new only if it currently
+// equals old, using WATCH/MULTI/EXEC and retrying on TxFailedErr when a
+// concurrent writer changes the key between the watch and the exec.
+func (r *RedisStore) CompareAndSwap(ctx context.Context, key string, old, new []byte) (bool, error) {
+	for attempt := 0; attempt < casMaxRetries; attempt++ {
+		swapped := false
+
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			current, err := tx.Get(ctx, key).Bytes()
+			if err != nil {
+				if err == redis.Nil {
+					return nil
+				}
+				return err
+			}
+
+			if !bytes.Equal(current, old) {
+				return nil
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, new, redis.KeepTTL)
+				return nil
+			})
+			if err == nil {
+				swapped = true
+			}
+			return err
+		}, key)
+
+		if err == nil {
+			return swapped, nil
+		}
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		return false, fmt.Errorf("failed to compare-and-swap key: %w", err)
+	}
+
+	return false, fmt.Errorf("compare-and-swap exceeded %d retries", casMaxRetries)
+}
+
+// Txn runs fn against a pipelined transaction: writes queue onto the
+// pipeline and commit atomically together when fn returns nil. Reads are
+// issued directly against the client rather than queued, since pipelined
+// commands aren't available to the caller until the pipeline executes.
+// readOnly has no distinct isolation mode in Redis and is accepted for
+// symmetry with the Postgres implementation.
+func (r *RedisStore) Txn(ctx context.Context, fn func(Tx) error, readOnly bool) error {
+	var fnErr error
+	tx := &redisTx{client: r.client}
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		tx.pipe = pipe
+		fnErr = fn(tx)
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to execute transaction: %w", err)
+	}
+
+	for _, check := range tx.setChecks {
+		if ok, _ := check.Result(); !ok {
+			return ErrKeyAlreadyExists
+		}
+	}
+
+	return nil
+}
+
+// redisTx implements Tx against a pipelined transaction: reads bypass the
+// pipeline and hit the client directly, writes queue onto pipe. setChecks
+// holds the SetNX commands queued by Set/SetWithTTL, whose create-only
+// result isn't known until the pipeline executes, so Txn inspects them
+// once it commits.
+type redisTx struct {
+	client    *redis.Client
+	pipe      redis.Pipeliner
+	setChecks []*redis.BoolCmd
+}
+
+func (t *redisTx) Get(ctx context.Context, key string) ([]byte, *TsMeta, error) {
+	val, err := t.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil, ErrKeyNotFound
+		}
+		return nil, nil, err
+	}
+	return val, &TsMeta{CreatedAt: time.Now().Format(time.RFC3339)}, nil
+}
+
+// Set enforces create-only semantics, matching RedisStore.Set. The
+// existence check bypasses the pipeline like Get does, so a rejected Set
+// returns ErrKeyAlreadyExists as fn's error before anything is queued -
+// aborting the whole transaction instead of letting sibling writes commit
+// first (TxPipelined never calls EXEC when fn returns an error). The SetNX
+// queued on the pipe is a safety net for the narrow race where another
+// writer creates the key between this check and EXEC; Txn reports that
+// case via setChecks once the pipeline has run.
+func (t *redisTx) Set(ctx context.Context, key string, value []byte) error {
+	exists, err := t.client.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check key existence: %w", err)
+	}
+	if exists > 0 {
+		return ErrKeyAlreadyExists
+	}
+	t.setChecks = append(t.setChecks, t.pipe.SetNX(ctx, key, value, 0))
+	return nil
+}
+
+func (t *redisTx) SetWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	exists, err := t.client.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check key existence: %w", err)
+	}
+	if exists > 0 {
+		return ErrKeyAlreadyExists
+	}
+	t.setChecks = append(t.setChecks, t.pipe.SetNX(ctx, key, value, duration))
+	return nil
+}
+
+func (t *redisTx) Update(ctx context.Context, key string, value []byte) error {
+	return t.pipe.Set(ctx, key, value, redis.KeepTTL).Err()
+}
+
+func (t *redisTx) UpdateWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) error {
+	return t.pipe.Set(ctx, key, value, duration).Err()
+}
+
+func (t *redisTx) Delete(ctx context.Context, key string) error {
+	return t.pipe.Del(ctx, key).Err()
+}
+
+func (t *redisTx) Scan(ctx context.Context, prefix string, cursor string, count int) ([]KeyValuePair, string, error) {
+	return nil, "", errors.New("scan is not supported inside a redis transaction")
+}
+
+// Subscribe streams changes to keys matching keyPattern via Redis keyspace
+// notifications: it enables them server-wide, then PSUBSCRIBEs to
+// __keyspace@<db>__:<pattern>. The returned channel closes when ctx is
+// canceled.
+func (r *RedisStore) Subscribe(ctx context.Context, keyPattern string) (<-chan KeyEvent, error) {
+	if err := r.client.ConfigSet(ctx, "notify-keyspace-events", "KEA").Err(); err != nil {
+		return nil, fmt.Errorf("failed to enable keyspace notifications: %w", err)
+	}
+
+	channelPrefix := fmt.Sprintf("__keyspace@%d__:", r.meta.DB)
+	pubsub := r.client.PSubscribe(ctx, channelPrefix+keyPattern)
+
+	events := make(chan KeyEvent)
+	go func() {
+		defer close(events)
+		defer pubsub.Close()
+
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+
+				op, known := redisKeyEventOp(msg.Payload)
+				if !known {
+					continue
+				}
+
+				event := KeyEvent{
+					Op:  op,
+					Key: strings.TrimPrefix(msg.Channel, channelPrefix),
+					At:  time.Now(),
+				}
+				if op != KeyEventDelete && op != KeyEventExpire {
+					if val, err := r.client.Get(ctx, event.Key).Bytes(); err == nil {
+						event.Value = val
+					}
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// redisKeyEventOp maps a keyspace notification command to the KeyEvent op
+// it represents. Commands with no equivalent (e.g. rename_from, sent for a
+// RENAME's source key) are ignored.
+func redisKeyEventOp(command string) (KeyEventOp, bool) {
+	switch command {
+	case "set", "setrange", "append", "getset", "incrby", "incrbyfloat", "decrby", "mset":
+		return KeyEventSet, true
+	case "expire", "pexpire", "expireat", "pexpireat", "persist", "rename_to":
+		return KeyEventUpdate, true
+	case "del", "unlink":
+		return KeyEventDelete, true
+	case "expired":
+		return KeyEventExpire, true
+	default:
+		return "", false
+	}
+}
+
 func (r *RedisStore) CloseConn() error {
 	return r.client.Close()
 }
@@ -0,0 +1,84 @@
+package store
+
+import "sync"
+
+// registry deduplicates concurrent opens of the same backing store: two
+// packages asking for the same Postgres database or Redis instance get the
+// same underlying connection, refcounted so CloseConn only tears it down
+// once the last caller releases it.
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*registryEntry{}
+)
+
+type registryEntry struct {
+	store    Store
+	refCount int
+}
+
+// acquireStore returns a ref-counted handle for key, creating the
+// underlying store with create if this is the first caller.
+func acquireStore(key string, create func() (Store, error)) (Store, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if entry, ok := registry[key]; ok {
+		entry.refCount++
+		return &refCountedStore{Store: entry.store, key: key}, nil
+	}
+
+	underlying, err := create()
+	if err != nil {
+		return nil, err
+	}
+
+	registry[key] = &registryEntry{store: underlying, refCount: 1}
+	return &refCountedStore{Store: underlying, key: key}, nil
+}
+
+// releaseStore drops one reference to key, closing the underlying store
+// once the last reference is released.
+func releaseStore(key string) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	entry, ok := registry[key]
+	if !ok {
+		return nil
+	}
+
+	entry.refCount--
+	if entry.refCount > 0 {
+		return nil
+	}
+
+	delete(registry, key)
+	return entry.store.CloseConn()
+}
+
+// refCountedStore wraps a shared Store so each holder's CloseConn only
+// releases its own reference rather than tearing down the connection
+// out from under other holders.
+type refCountedStore struct {
+	Store
+	key string
+}
+
+func (r *refCountedStore) CloseConn() error {
+	return releaseStore(r.key)
+}
+
+// NewFromURI opens (or attaches to an existing, registry-shared) store for
+// the given provider from a connection URI, e.g.
+// "postgres://user:pw@host:port/db?sslmode=disable&tz=UTC&cron=30s&table=kv"
+// or "redis://:pw@host:port/db?pool_size=10".
+func NewFromURI(provider, dsn string) (Store, error) {
+	switch provider {
+	case "redis":
+		return newRedisStoreFromURI(dsn)
+	case "pgsql", "postgres":
+		return newPostgresStoreFromURI(dsn)
+	}
+
+	return nil, ErrProviderNotFound
+}
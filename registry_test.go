@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+// TestNewBadgerStoreSharesConnectionByPath exercises the refcounted registry
+// end to end: two callers for the same path share one underlying
+// BadgerStore, and the underlying connection is only torn down once every
+// caller has released it.
+func TestNewBadgerStoreSharesConnectionByPath(t *testing.T) {
+	ctx := context.Background()
+	meta := BadgerMeta{Path: t.TempDir()}
+
+	first, err := NewBadgerStore(meta)
+	if err != nil {
+		t.Fatalf("NewBadgerStore (first): %v", err)
+	}
+
+	second, err := NewBadgerStore(meta)
+	if err != nil {
+		t.Fatalf("NewBadgerStore (second): %v", err)
+	}
+
+	if err := first.Set(ctx, "k", []byte("v")); err != nil {
+		t.Fatalf("Set via first handle: %v", err)
+	}
+
+	value, _, err := second.Get(ctx, "k")
+	if err != nil {
+		t.Fatalf("Get via second handle: %v", err)
+	}
+	if string(value) != "v" {
+		t.Fatalf("Get via second handle: got %q, want %q", value, "v")
+	}
+
+	if err := first.CloseConn(); err != nil {
+		t.Fatalf("CloseConn (first): %v", err)
+	}
+
+	// The underlying store must still be open: second holds a reference.
+	if _, _, err := second.Get(ctx, "k"); err != nil {
+		t.Fatalf("Get via second handle after first released: %v", err)
+	}
+
+	if err := second.CloseConn(); err != nil {
+		t.Fatalf("CloseConn (second): %v", err)
+	}
+
+	key := badgerRegistryKey(meta)
+	registryMu.Lock()
+	_, stillRegistered := registry[key]
+	registryMu.Unlock()
+	if stillRegistered {
+		t.Fatal("registry entry was not removed after the last reference was released")
+	}
+}
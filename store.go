@@ -36,30 +36,107 @@ type GetKeyValueByTimestampC struct {
 	Err      error
 }
 
+type KeyEventOp string
+
+const (
+	KeyEventSet    KeyEventOp = "set"
+	KeyEventUpdate KeyEventOp = "update"
+	KeyEventDelete KeyEventOp = "delete"
+	KeyEventExpire KeyEventOp = "expire"
+)
+
+// KeyEvent describes a single change to a key, delivered to subscribers of
+// Store.Subscribe.
+type KeyEvent struct {
+	Op    KeyEventOp
+	Key   string
+	Value []byte
+	At    time.Time
+}
+
 type StoreType any
 
 type Store interface {
+	Get(ctx context.Context, key string) (value []byte, meta *TsMeta, err error)
 	Set(ctx context.Context, key string, value []byte) (err error)
 	SetWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) (err error)
+	Update(ctx context.Context, key string, value []byte) (err error)
+	UpdateWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) (err error)
 	Delete(ctx context.Context, key string) (err error)
+	GetNextID(ctx context.Context) (id int64, err error)
+	MGet(ctx context.Context, keys []string) (pairs []KeyValuePair, err error)
+	MSet(ctx context.Context, pairs []KeyValuePair) (err error)
+	Scan(ctx context.Context, prefix string, cursor string, count int) (pairs []KeyValuePair, nextCursor string, err error)
+	CompareAndSwap(ctx context.Context, key string, old, new []byte) (swapped bool, err error)
+	Txn(ctx context.Context, fn func(Tx) error, readOnly bool) (err error)
+	Subscribe(ctx context.Context, keyPattern string) (events <-chan KeyEvent, err error)
 	CloseConn() (err error)
 }
 
-func InitializeStore(provider string, meta StoreType) (Store, error) {
+// Tx is the surface available inside Store.Txn. A read-only Txn gives
+// callers a stable snapshot to page through with Scan/Get without phantom
+// rows from a concurrent TTL cleaner; a read-write Txn commits its writes
+// atomically with the rest of the transaction. On Redis, reads inside Txn
+// are not isolated from concurrent writers and Scan returns an error.
+type Tx interface {
+	Get(ctx context.Context, key string) (value []byte, meta *TsMeta, err error)
+	Set(ctx context.Context, key string, value []byte) (err error)
+	SetWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) (err error)
+	Update(ctx context.Context, key string, value []byte) (err error)
+	UpdateWithTTL(ctx context.Context, key string, value []byte, duration time.Duration) (err error)
+	Delete(ctx context.Context, key string) (err error)
+	Scan(ctx context.Context, prefix string, cursor string, count int) (pairs []KeyValuePair, nextCursor string, err error)
+}
+
+// InitOption configures optional behavior for InitializeStore, such as
+// wrapping the returned Store with observability.
+type InitOption func(*initConfig)
+
+type initConfig struct {
+	observe     bool
+	observeOpts ObserveOptions
+}
+
+// WithObservability wraps the store InitializeStore returns with Observed,
+// using opts (Provider defaults to the requested provider name if unset).
+func WithObservability(opts ObserveOptions) InitOption {
+	return func(cfg *initConfig) {
+		cfg.observe = true
+		cfg.observeOpts = opts
+	}
+}
+
+func InitializeStore(provider string, meta StoreType, opts ...InitOption) (Store, error) {
+	var cfg initConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	store, err := newStoreForProvider(provider, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.observe {
+		if cfg.observeOpts.Provider == "" {
+			cfg.observeOpts.Provider = provider
+		}
+		return Observed(store, cfg.observeOpts), nil
+	}
+
+	return store, nil
+}
+
+func newStoreForProvider(provider string, meta StoreType) (Store, error) {
 	switch provider {
 	case "redis":
-		store, err := NewRedisStore(meta.(RedisMeta))
-		if err != nil {
-			return nil, err
-		}
-		return store, nil
+		return NewRedisStore(meta.(RedisMeta))
 
 	case "pgsql":
-		store, err := NewPostgresStore(meta.(PgMeta))
-		if err != nil {
-			return nil, err
-		}
-		return store, nil
+		return NewPostgresStore(meta.(PgMeta))
+
+	case "badger":
+		return NewBadgerStore(meta.(BadgerMeta))
 	}
 
 	return nil, ErrProviderNotFound